@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/cinus-ue/securekit/kit"
+	"github.com/cinus-ue/securekit/kit/suite"
+	"github.com/cinus-ue/securekit/util"
+	"github.com/urfave/cli/v2"
+)
+
+var Arc = &cli.Command{
+	Name:      "arc",
+	Usage:     "Zip and encrypt files/directories into a single .skt archive",
+	ArgsUsage: "path [path...]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Required: true, Usage: "archive file to create"},
+	},
+	Action: ArcAction,
+}
+
+var Unarc = &cli.Command{
+	Name:  "unarc",
+	Usage: "Decrypt and unzip a .skt archive created by arc",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "path", Aliases: []string{"p"}, Required: true, Usage: "archive file to extract"},
+		&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Required: true, Usage: "directory to extract into"},
+	},
+	Action: UnarcAction,
+}
+
+func ArcAction(ctx *cli.Context) error {
+	passphrase := []byte(util.GetInput("Passphrase:"))
+	return kit.ArchiveEncrypt(ctx.Args().Slice(), ctx.String("out"), passphrase, suite.Aes256CtrHmac)
+}
+
+func UnarcAction(ctx *cli.Context) error {
+	passphrase := []byte(util.GetInput("Passphrase:"))
+	return kit.ArchiveDecrypt(ctx.String("path"), ctx.String("out"), passphrase, suite.Aes256CtrHmac)
+}
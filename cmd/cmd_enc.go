@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/cinus-ue/securekit/kit"
+	"github.com/cinus-ue/securekit/kit/path"
+	"github.com/cinus-ue/securekit/progress"
+	"github.com/cinus-ue/securekit/util"
+	"github.com/urfave/cli/v2"
+)
+
+var Enc = &cli.Command{
+	Name:  "enc",
+	Usage: "Encrypt a file or directory",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "path", Aliases: []string{"p"}, Required: true, Usage: "file or directory to encrypt"},
+		&cli.BoolFlag{Name: "delete", Aliases: []string{"d"}, Usage: "delete the source file after encryption"},
+		&cli.BoolFlag{Name: "paranoid", Usage: "use the XChaCha20+Serpent cascade instead of AES-256-CTR-HMAC"},
+		&cli.BoolFlag{Name: "rs", Usage: "wrap the ciphertext in a Reed-Solomon FEC layer for bit-rot resilience"},
+		&cli.StringSliceFlag{Name: "keyfile", Usage: "require this keyfile in addition to the passphrase (repeatable)"},
+		&cli.BoolFlag{Name: "keyfile-ordered", Value: true, Usage: "keyfile order matters; pass false to make it order-independent"},
+		&cli.BoolFlag{Name: "deniable", Usage: "write a headerless file indistinguishable from random data; incompatible with paranoid, rs and keyfile"},
+		&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "output path for --deniable (required); a fixed extension would give the file away"},
+	},
+	Action: EncAction,
+}
+
+var Dec = &cli.Command{
+	Name:  "dec",
+	Usage: "Decrypt a file or directory",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "path", Aliases: []string{"p"}, Required: true, Usage: "file or directory to decrypt"},
+		&cli.BoolFlag{Name: "delete", Aliases: []string{"d"}, Usage: "delete the .skt file after decryption"},
+		&cli.BoolFlag{Name: "fix", Usage: "substitute zero-filled data for unrecoverable Reed-Solomon blocks instead of aborting"},
+		&cli.StringSliceFlag{Name: "keyfile", Usage: "keyfile required by the file being decrypted (repeatable)"},
+		&cli.BoolFlag{Name: "deniable", Usage: "decrypt a file written with enc --deniable; required since such files carry no identifying header"},
+		&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "output path for --deniable (required)"},
+	},
+	Action: DecAction,
+}
+
+func EncAction(ctx *cli.Context) error {
+	passphrase := []byte(util.GetInput("Passphrase:"))
+	paranoid, rs, delete := ctx.Bool("paranoid"), ctx.Bool("rs"), ctx.Bool("delete")
+	keyfiles, ordered := ctx.StringSlice("keyfile"), ctx.Bool("keyfile-ordered")
+	deniable := ctx.Bool("deniable")
+	if paranoid && (rs || len(keyfiles) > 0 || deniable) {
+		return fmt.Errorf("--paranoid does not support --rs, --keyfile or --deniable")
+	}
+	if deniable && (rs || len(keyfiles) > 0) {
+		return fmt.Errorf("--deniable does not support --rs or --keyfile")
+	}
+	if deniable {
+		out := ctx.String("out")
+		if out == "" {
+			return fmt.Errorf("--deniable requires --out")
+		}
+		return kit.DeniableFileEncrypt(ctx.String("path"), out, passphrase, delete)
+	}
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return walk(ctx.String("path"), func(file string) error {
+		if paranoid {
+			return kit.CascadeFileEncrypt(file, passphrase, delete)
+		}
+		bar := progress.Bar(file, 0)
+		return kit.AEADFileEncryptCtx(runCtx, file, passphrase, delete, rs, keyfiles, ordered, bar)
+	})
+}
+
+func DecAction(ctx *cli.Context) error {
+	passphrase := []byte(util.GetInput("Passphrase:"))
+	fix, delete := ctx.Bool("fix"), ctx.Bool("delete")
+	keyfiles := ctx.StringSlice("keyfile")
+	if ctx.Bool("deniable") {
+		out := ctx.String("out")
+		if out == "" {
+			return fmt.Errorf("--deniable requires --out")
+		}
+		return kit.DeniableFileDecrypt(ctx.String("path"), out, passphrase, delete)
+	}
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return walk(ctx.String("path"), func(file string) error {
+		bar := progress.Bar(file, 0)
+		return kit.FileDecryptCtx(runCtx, file, passphrase, delete, fix, keyfiles, bar)
+	})
+}
+
+// walk applies fn to p if it is a regular file, or to every regular file
+// beneath p if it is a directory, reporting each path as it is processed.
+func walk(p string, fn func(file string) error) error {
+	if !path.ValidateFile(p) {
+		return fmt.Errorf("path not found: %s", p)
+	}
+	files, err := path.Scan(p, true)
+	if err != nil {
+		return err
+	}
+	for !files.IsEmpty() {
+		file := files.Pop().(string)
+		fmt.Println(file)
+		if err := fn(file); err != nil {
+			fmt.Printf("%s: %v\n", file, err)
+		}
+	}
+	return nil
+}
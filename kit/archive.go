@@ -0,0 +1,208 @@
+package kit
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cinus-ue/securekit/kit/suite"
+)
+
+// manifestName is a reserved zip entry written first, ahead of the real
+// files, holding the file count and total uncompressed size so a reader
+// can learn them without a second pass over paths. ArchiveDecrypt skips
+// this entry without reading it.
+const manifestName = ".skt-manifest"
+
+// SKTArchiveVersion marks a .skt file as a zip-then-encrypt archive
+// produced by ArchiveEncrypt, as opposed to a single encrypted file.
+var SKTArchiveVersion = []byte{0x53, 0x4B, 0x54, 0x05, 0x01}
+
+// archiveFile is one input file discovered by collectFiles, paired with
+// the relative path it should be stored under in the zip.
+type archiveFile struct {
+	abs  string
+	name string
+}
+
+// ArchiveEncrypt zips paths - files and, recursively, directories - and
+// streams the zip directly into an AEAD-encrypted blob at out, so an
+// entire directory tree becomes one authenticated .skt file without ever
+// writing a plaintext zip to disk.
+func ArchiveEncrypt(paths []string, out string, passphrase []byte, algo suite.Algorithm) error {
+	files, total, err := collectFiles(paths)
+	if err != nil {
+		return err
+	}
+
+	dest, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	if _, err = dest.Write(SKTArchiveVersion); err != nil {
+		dest.Close()
+		os.Remove(out)
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		zw := zip.NewWriter(pw)
+		err := writeManifest(zw, len(files), total)
+		if err == nil {
+			err = writeZipEntries(zw, files)
+		}
+		if cerr := zw.Close(); err == nil {
+			err = cerr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	err = suite.StreamEnc(pr, dest, passphrase, algo)
+	dest.Close()
+	// suite.StreamEnc may return before draining pr (e.g. a dest write
+	// error); CloseWithError unblocks the zip goroutine's pending pw.Write
+	// instead of leaving it stuck forever, and is a no-op if pr is already
+	// closed (EOF/CloseWithError from the goroutine itself).
+	pr.CloseWithError(err)
+	if err != nil {
+		os.Remove(out)
+		return err
+	}
+	return nil
+}
+
+// ArchiveDecrypt reverses ArchiveEncrypt, extracting every file into dir
+// with its stored relative path. The decrypted zip is buffered in memory
+// before extraction, since archive/zip.Reader needs random access to the
+// central directory that suite.StreamDec's forward-only output can't
+// provide.
+func ArchiveDecrypt(in, dir string, passphrase []byte, algo suite.Algorithm) error {
+	src, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	version := make([]byte, len(SKTArchiveVersion))
+	if _, err = io.ReadFull(src, version); err != nil {
+		return err
+	}
+	if !bytes.Equal(version, SKTArchiveVersion) {
+		return fmt.Errorf("not a securekit archive")
+	}
+
+	var plain bytes.Buffer
+	if err = suite.StreamDec(src, &plain, passphrase, algo); err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(plain.Bytes()), int64(plain.Len()))
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.Name == manifestName {
+			continue
+		}
+		if err = extractZipEntry(f, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectFiles walks paths (recursing into directories) and returns every
+// regular file found, alongside the total size of their contents.
+func collectFiles(paths []string) ([]archiveFile, int64, error) {
+	var files []archiveFile
+	var total int64
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !info.IsDir() {
+			files = append(files, archiveFile{abs: p, name: filepath.Base(p)})
+			total += info.Size()
+			continue
+		}
+		root := filepath.Base(p)
+		err = filepath.Walk(p, func(walked string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() {
+				return err
+			}
+			rel, rerr := filepath.Rel(p, walked)
+			if rerr != nil {
+				return rerr
+			}
+			files = append(files, archiveFile{abs: walked, name: filepath.Join(root, rel)})
+			total += fi.Size()
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return files, total, nil
+}
+
+func writeManifest(zw *zip.Writer, count int, total int64) error {
+	w, err := zw.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	manifest := make([]byte, 12)
+	binary.BigEndian.PutUint32(manifest[:4], uint32(count))
+	binary.BigEndian.PutUint64(manifest[4:], uint64(total))
+	_, err = w.Write(manifest)
+	return err
+}
+
+func writeZipEntries(zw *zip.Writer, files []archiveFile) error {
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(f.abs)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipEntry(f *zip.File, dir string) error {
+	target := filepath.Join(dir, f.Name)
+	cleanDir := filepath.Clean(dir)
+	if target != cleanDir && !strings.HasPrefix(target, cleanDir+string(os.PathSeparator)) {
+		return fmt.Errorf("zip entry escapes destination: %s", f.Name)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, r)
+	return err
+}
@@ -2,9 +2,11 @@ package kit
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -20,14 +22,108 @@ const (
 	pSizeLen = 8
 	sktExt   = ".skt"
 	aesAlgo  = suite.Aes256Ctr
+	aeadAlgo = suite.Aes256CtrHmac
 )
 
 var (
-	SKTAESVersion = []byte{0x53, 0x4B, 0x54, 0x00, 0x02}
-	SKTRSAVersion = []byte{0x53, 0x4B, 0x54, 0x01, 0x02}
-	SKTRC4Version = []byte{0x53, 0x4B, 0x54, 0x02, 0x01}
+	SKTAESVersion     = []byte{0x53, 0x4B, 0x54, 0x00, 0x03}
+	SKTRSAVersion     = []byte{0x53, 0x4B, 0x54, 0x01, 0x03}
+	SKTRC4Version     = []byte{0x53, 0x4B, 0x54, 0x02, 0x01}
+	SKTAEADVersion    = []byte{0x53, 0x4B, 0x54, 0x03, 0x02}
+	SKTCascadeVersion = []byte{0x53, 0x4B, 0x54, 0x04, 0x01}
+
+	// ...VersionLegacy mark files written before the Argon2id cost
+	// parameters were embedded in the header. beforeDecryptCompat accepts
+	// either the current or the legacy version and reports which one
+	// matched, so decryption can pick StreamDec vs StreamDecLegacy.
+	SKTAESVersionLegacy  = []byte{0x53, 0x4B, 0x54, 0x00, 0x02}
+	SKTRSAVersionLegacy  = []byte{0x53, 0x4B, 0x54, 0x01, 0x02}
+	SKTAEADVersionLegacy = []byte{0x53, 0x4B, 0x54, 0x03, 0x01}
 )
 
+// rsFlagBit is OR'd into the last byte of an SKT version to record that the
+// ciphertext body is wrapped in a suite.ReedSolomonWriter/Reader pipeline,
+// so decryption transparently picks the RS-aware path without a separate
+// header field.
+const rsFlagBit byte = 0x80
+
+func withRSFlag(version []byte, rs bool) []byte {
+	v := append([]byte(nil), version...)
+	if rs {
+		v[len(v)-1] |= rsFlagBit
+	}
+	return v
+}
+
+func hasRSFlag(version []byte) bool {
+	return version[len(version)-1]&rsFlagBit != 0
+}
+
+func stripRSFlag(version []byte) []byte {
+	v := append([]byte(nil), version...)
+	v[len(v)-1] &^= rsFlagBit
+	return v
+}
+
+// kfFlagBit is OR'd into the last byte of an SKT version (alongside
+// rsFlagBit) to record that a keyfile header - required keyfile count plus
+// an order-matters byte - follows the version, per chunk0-5.
+const kfFlagBit byte = 0x40
+
+func withKFFlag(version []byte, hasKeyfiles bool) []byte {
+	v := append([]byte(nil), version...)
+	if hasKeyfiles {
+		v[len(v)-1] |= kfFlagBit
+	}
+	return v
+}
+
+func hasKFFlag(version []byte) bool {
+	return version[len(version)-1]&kfFlagBit != 0
+}
+
+func stripFlags(version []byte) []byte {
+	v := append([]byte(nil), version...)
+	v[len(v)-1] &^= rsFlagBit | kfFlagBit
+	return v
+}
+
+// writeKeyfileHeader writes the keyfile count and order-matters byte and
+// returns the XOR secret to mix into the derived key. It is a no-op,
+// returning a nil secret, when keyfiles is empty.
+func writeKeyfileHeader(dest io.Writer, keyfiles []string, ordered bool) ([]byte, error) {
+	if len(keyfiles) == 0 {
+		return nil, nil
+	}
+	secret, err := CombineKeyfiles(keyfiles, ordered)
+	if err != nil {
+		return nil, err
+	}
+	orderedByte := byte(0)
+	if ordered {
+		orderedByte = 1
+	}
+	if _, err := dest.Write([]byte{byte(len(keyfiles)), orderedByte}); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// readKeyfileHeader reads the keyfile count and order-matters byte written
+// by writeKeyfileHeader, rejects a keyfiles slice of the wrong length, and
+// returns the XOR secret to mix into the derived key.
+func readKeyfileHeader(src io.Reader, keyfiles []string) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return nil, err
+	}
+	count, ordered := int(header[0]), header[1] == 1
+	if count != len(keyfiles) {
+		return nil, fmt.Errorf("this file requires %d keyfile(s), got %d", count, len(keyfiles))
+	}
+	return CombineKeyfiles(keyfiles, ordered)
+}
+
 func versionCheck(src io.Reader, versionRequirement []byte) error {
 	version := make([]byte, len(versionRequirement))
 	_, _ = src.Read(version)
@@ -59,6 +155,60 @@ func beforeDecrypt(filepath string, version []byte) (src, dest *os.File, err err
 	return
 }
 
+// beforeDecryptCompat is beforeDecrypt, but accepts a file written under
+// either the current or the legacy SKT version. It returns isLegacy=true
+// when the file matched the legacy version, so the caller can switch to
+// suite.StreamDecLegacy.
+func beforeDecryptCompat(filepath string, current, legacy []byte) (src, dest *os.File, isLegacy bool, err error) {
+	src, err = os.Open(filepath)
+	if err != nil {
+		return
+	}
+	version := make([]byte, len(current))
+	if _, err = src.Read(version); err != nil {
+		return
+	}
+	switch {
+	case bytes.Equal(version, current):
+		isLegacy = false
+	case bytes.Equal(version, legacy):
+		isLegacy = true
+	default:
+		err = errors.New("version mismatch error")
+		return
+	}
+	dest, err = os.Create(strings.TrimSuffix(filepath, sktExt))
+	return
+}
+
+// beforeDecryptCompatTemp is beforeDecryptCompat, but creates dest at
+// finalName+".partial" instead of finalName itself, so a caller that needs
+// to verify a trailing MAC before the plaintext becomes visible under its
+// real name can decrypt into the partial file and only os.Rename it into
+// place once the tag checks out.
+func beforeDecryptCompatTemp(filepath string, current, legacy []byte) (src, dest *os.File, finalName string, isLegacy bool, err error) {
+	src, err = os.Open(filepath)
+	if err != nil {
+		return
+	}
+	version := make([]byte, len(current))
+	if _, err = src.Read(version); err != nil {
+		return
+	}
+	switch {
+	case bytes.Equal(version, current):
+		isLegacy = false
+	case bytes.Equal(version, legacy):
+		isLegacy = true
+	default:
+		err = errors.New("version mismatch error")
+		return
+	}
+	finalName = strings.TrimSuffix(filepath, sktExt)
+	dest, err = os.Create(finalName + ".partial")
+	return
+}
+
 func closeFile(src, dest *os.File) {
 	src.Close()
 	dest.Close()
@@ -115,7 +265,245 @@ func RC4FileDecrypt(filepath string, passphrase []byte, delete bool) error {
 	return nil
 }
 
-func AESFileEncrypt(filepath string, passphrase []byte, delete bool) error {
+// AESFileEncrypt encrypts filepath with AES-256-CTR. When keyfiles is
+// non-empty, the effective master secret also requires every listed
+// keyfile (see CombineKeyfiles), giving multi-factor encryption.
+func AESFileEncrypt(filepath string, passphrase []byte, delete bool, keyfiles []string, ordered bool) error {
+	if path.Ext(filepath) == sktExt {
+		return nil
+	}
+	src, dest, err := beforeEncrypt(filepath)
+	if err != nil {
+		return err
+	}
+	dest.Write(withKFFlag(SKTAESVersion, len(keyfiles) != 0))
+	secret, err := writeKeyfileHeader(dest, keyfiles, ordered)
+	if err != nil {
+		closeFile(src, dest)
+		os.Remove(dest.Name())
+		return err
+	}
+	err = suite.StreamEncMF(src, dest, passphrase, secret, aesAlgo)
+	closeFile(src, dest)
+	if err != nil {
+		os.Remove(dest.Name())
+		return err
+	}
+	deleteFile(src, delete)
+	return nil
+}
+
+// AESFileDecrypt reverses AESFileEncrypt. keyfiles must list the same
+// paths used to encrypt (order doesn't matter unless the file was written
+// with ordered=true); a count mismatch is rejected before any AES work.
+func AESFileDecrypt(filepath string, passphrase []byte, delete bool, keyfiles []string) error {
+	if path.Ext(filepath) != sktExt {
+		return nil
+	}
+	version, err := peekVersion(filepath)
+	if err != nil {
+		return err
+	}
+	hasKF := hasKFFlag(version)
+	src, dest, isLegacy, err := beforeDecryptCompat(filepath, withKFFlag(SKTAESVersion, hasKF), withKFFlag(SKTAESVersionLegacy, hasKF))
+	if err != nil {
+		return err
+	}
+	var secret []byte
+	if hasKF {
+		secret, err = readKeyfileHeader(src, keyfiles)
+		if err != nil {
+			closeFile(src, dest)
+			os.Remove(dest.Name())
+			return err
+		}
+	}
+	if isLegacy {
+		err = suite.StreamDecLegacy(src, dest, passphrase, aesAlgo)
+	} else {
+		err = suite.StreamDecMF(src, dest, passphrase, secret, aesAlgo)
+	}
+	closeFile(src, dest)
+	if err != nil {
+		os.Remove(dest.Name())
+		return err
+	}
+	deleteFile(src, delete)
+	return nil
+}
+
+// AEADFileEncrypt encrypts filepath with AES-256-CTR-HMAC (suite.Aes256CtrHmac),
+// the authenticated replacement for the plain CTR mode used by AESFileEncrypt.
+// It is the mode the CLI defaults to, since unlike CTR alone it fails loudly
+// on tampering instead of decrypting to garbage. When rs is true, the
+// ciphertext body is additionally wrapped in a suite.ReedSolomonWriter so
+// the .skt file tolerates some bit rot, at the cost of ~8% overhead. When
+// keyfiles is non-empty, decrypting additionally requires every listed
+// keyfile (see CombineKeyfiles).
+func AEADFileEncrypt(filepath string, passphrase []byte, delete, rs bool, keyfiles []string, ordered bool) error {
+	return AEADFileEncryptCtx(context.Background(), filepath, passphrase, delete, rs, keyfiles, ordered, nil)
+}
+
+// AEADFileEncryptCtx is AEADFileEncrypt, but returns promptly (removing the
+// partial output file) if ctx is canceled mid-stream, and calls progress
+// (if non-nil) as plaintext is read.
+func AEADFileEncryptCtx(ctx context.Context, filepath string, passphrase []byte, delete, rs bool, keyfiles []string, ordered bool, progress suite.ProgressFunc) error {
+	if path.Ext(filepath) == sktExt {
+		return nil
+	}
+	src, dest, err := beforeEncrypt(filepath)
+	if err != nil {
+		return err
+	}
+	dest.Write(withKFFlag(withRSFlag(SKTAEADVersion, rs), len(keyfiles) != 0))
+	secret, err := writeKeyfileHeader(dest, keyfiles, ordered)
+	if err != nil {
+		closeFile(src, dest)
+		os.Remove(dest.Name())
+		return err
+	}
+	var total int64
+	if info, ierr := src.Stat(); ierr == nil {
+		total = info.Size()
+	}
+	err = streamEncRSCtx(ctx, src, dest, passphrase, secret, aeadAlgo, rs, total, progress)
+	closeFile(src, dest)
+	if err != nil {
+		os.Remove(dest.Name())
+		return err
+	}
+	deleteFile(src, delete)
+	return nil
+}
+
+// AEADFileDecrypt reverses AEADFileEncrypt. The HMAC tag is checked as the
+// last bytes of the file are read, so a tampered file surfaces
+// suite.ErrAuthFailed instead of silently producing corrupt plaintext.
+// When the file was written with rs=true, a codeword with a single
+// corrupted byte is repaired transparently and still authenticates
+// normally; beyond that, a block is unrecoverable (suite.ErrRSUnrecoverable)
+// unless fix is true, in which case it is zero-filled and decryption
+// continues - but the result is necessarily cut loose from the trailing
+// HMAC tag, which will then fail. Rather than discard that best-effort
+// plaintext along with the rest of the file, fix still commits it under
+// its real name and returns a non-nil error wrapping suite.ErrAuthFailed,
+// so the caller can tell "recovered, but verify the damaged region
+// yourself" apart from every other decrypt failure, which still removes
+// the output. keyfiles must list the same paths used to encrypt when the
+// file carries a keyfile header.
+func AEADFileDecrypt(filepath string, passphrase []byte, delete, fix bool, keyfiles []string) error {
+	return AEADFileDecryptCtx(context.Background(), filepath, passphrase, delete, fix, keyfiles, nil)
+}
+
+// AEADFileDecryptCtx is AEADFileDecrypt, but returns promptly (removing the
+// partial output file) if ctx is canceled mid-stream, and calls progress
+// (if non-nil) as ciphertext is read. Plaintext is decrypted into a
+// finalName+".partial" temp file; it is moved to its real name once the
+// trailing HMAC tag has verified, or - when fix recovered a block by
+// substitution - once decryption has finished despite the tag not
+// verifying, so a concurrent reader can never observe plaintext under the
+// file's final name before one of those two outcomes. See
+// beforeDecryptCompatTemp.
+func AEADFileDecryptCtx(ctx context.Context, filepath string, passphrase []byte, delete, fix bool, keyfiles []string, progress suite.ProgressFunc) error {
+	if path.Ext(filepath) != sktExt {
+		return nil
+	}
+	version, err := peekVersion(filepath)
+	if err != nil {
+		return err
+	}
+	rs, hasKF := hasRSFlag(version), hasKFFlag(version)
+	current := withKFFlag(withRSFlag(SKTAEADVersion, rs), hasKF)
+	legacy := withKFFlag(withRSFlag(SKTAEADVersionLegacy, rs), hasKF)
+	src, dest, finalName, isLegacy, err := beforeDecryptCompatTemp(filepath, current, legacy)
+	if err != nil {
+		return err
+	}
+	var secret []byte
+	if hasKF {
+		secret, err = readKeyfileHeader(src, keyfiles)
+		if err != nil {
+			closeFile(src, dest)
+			os.Remove(dest.Name())
+			return err
+		}
+	}
+	var total int64
+	if info, ierr := src.Stat(); ierr == nil {
+		total = info.Size()
+	}
+	var lossy bool
+	if isLegacy {
+		err = suite.StreamDecLegacy(src, dest, passphrase, aeadAlgo)
+	} else {
+		lossy, err = streamDecRSCtx(ctx, src, dest, passphrase, secret, aeadAlgo, rs, fix, total, progress)
+	}
+	closeFile(src, dest)
+	if err != nil && !(lossy && errors.Is(err, suite.ErrAuthFailed)) {
+		os.Remove(dest.Name())
+		return err
+	}
+	if rerr := os.Rename(dest.Name(), finalName); rerr != nil {
+		os.Remove(dest.Name())
+		return rerr
+	}
+	deleteFile(src, delete)
+	if lossy && err != nil {
+		// -fix recovered this file by substituting zero-filled data for at
+		// least one unrecoverable Reed-Solomon block, so the trailing AEAD
+		// tag could never match; surface that as a non-fatal warning rather
+		// than throwing away the best-effort plaintext we just committed.
+		return fmt.Errorf("%s: recovered with data loss (reed-solomon fix): %w", finalName, err)
+	}
+	return nil
+}
+
+// streamEncRSCtx is suite.StreamEncryptCtx-equivalent for the keyfile-aware
+// path, optionally wrapping dest in a suite.ReedSolomonWriter first. There
+// is no keyfile-aware StreamEncryptCtx, so this mixes the keyfile secret in
+// manually via the same ctxReader machinery StreamEncryptCtx uses.
+func streamEncRSCtx(ctx context.Context, src io.Reader, dest io.Writer, passphrase, keyfileSecret []byte, algorithm suite.Algorithm, rs bool, total int64, progress suite.ProgressFunc) error {
+	cr := suite.NewCtxReader(ctx, src, total, progress)
+	if !rs {
+		return suite.StreamEncMF(cr, dest, passphrase, keyfileSecret, algorithm)
+	}
+	rw, err := suite.NewReedSolomonWriter(dest)
+	if err != nil {
+		return err
+	}
+	if err = suite.StreamEncMF(cr, rw, passphrase, keyfileSecret, algorithm); err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+// streamDecRSCtx reverses streamEncRSCtx, with the same context
+// cancellation and progress reporting, optionally reading src through a
+// suite.ReedSolomonReader first. The returned lossy flag is true only when
+// rs and fix are both set and at least one codeword had more damage than a
+// single erasure could locate, in which case err (if non-nil) is expected
+// to be suite.ErrAuthFailed: the substituted zero-filled block can never
+// re-authenticate, but the caller still has a best-effort plaintext worth
+// keeping - see AEADFileDecryptCtx.
+func streamDecRSCtx(ctx context.Context, src io.Reader, dest io.Writer, passphrase, keyfileSecret []byte, algorithm suite.Algorithm, rs, fix bool, total int64, progress suite.ProgressFunc) (lossy bool, err error) {
+	cr := suite.NewCtxReader(ctx, src, total, progress)
+	if !rs {
+		return false, suite.StreamDecMF(cr, dest, passphrase, keyfileSecret, algorithm)
+	}
+	rr, err := suite.NewReedSolomonReader(cr, fix)
+	if err != nil {
+		return false, err
+	}
+	err = suite.StreamDecMF(rr, dest, passphrase, keyfileSecret, algorithm)
+	return rr.Lossy(), err
+}
+
+// CascadeFileEncrypt encrypts filepath with suite.CascadeXChaChaSerpent,
+// layering XChaCha20 and Serpent-CTR under independently derived keys so
+// that a break of either cipher alone does not expose the plaintext. It is
+// meant for the `-paranoid` CLI flag, where the extra cipher outweighs the
+// cost of buffering the file in memory.
+func CascadeFileEncrypt(filepath string, passphrase []byte, delete bool) error {
 	if path.Ext(filepath) == sktExt {
 		return nil
 	}
@@ -123,8 +511,8 @@ func AESFileEncrypt(filepath string, passphrase []byte, delete bool) error {
 	if err != nil {
 		return err
 	}
-	dest.Write(SKTAESVersion)
-	err = suite.StreamEnc(src, dest, passphrase, aesAlgo)
+	dest.Write(SKTCascadeVersion)
+	err = suite.StreamEnc(src, dest, passphrase, suite.CascadeXChaChaSerpent)
 	closeFile(src, dest)
 	if err != nil {
 		os.Remove(dest.Name())
@@ -134,15 +522,16 @@ func AESFileEncrypt(filepath string, passphrase []byte, delete bool) error {
 	return nil
 }
 
-func AESFileDecrypt(filepath string, passphrase []byte, delete bool) error {
+// CascadeFileDecrypt reverses CascadeFileEncrypt.
+func CascadeFileDecrypt(filepath string, passphrase []byte, delete bool) error {
 	if path.Ext(filepath) != sktExt {
 		return nil
 	}
-	src, dest, err := beforeDecrypt(filepath, SKTAESVersion)
+	src, dest, err := beforeDecrypt(filepath, SKTCascadeVersion)
 	if err != nil {
 		return err
 	}
-	err = suite.StreamDec(src, dest, passphrase, aesAlgo)
+	err = suite.StreamDec(src, dest, passphrase, suite.CascadeXChaChaSerpent)
 	closeFile(src, dest)
 	if err != nil {
 		os.Remove(dest.Name())
@@ -152,7 +541,61 @@ func AESFileDecrypt(filepath string, passphrase []byte, delete bool) error {
 	return nil
 }
 
-func RSAFileEncrypt(filepath, keyfile string, delete bool) error {
+// FileDecrypt sniffs the SKT version header of filepath and dispatches to
+// whichever *FileDecrypt handles it, so CLI callers don't need to know in
+// advance which mode a given .skt file was written with. It does not cover
+// the RSA mode, which requires a private-key file the caller must supply
+// explicitly. keyfiles is forwarded to modes that support multi-factor
+// decryption (AES, AEAD); pass nil if the file wasn't encrypted with any.
+func FileDecrypt(filepath string, passphrase []byte, delete, fix bool, keyfiles []string) error {
+	return FileDecryptCtx(context.Background(), filepath, passphrase, delete, fix, keyfiles, nil)
+}
+
+// FileDecryptCtx is FileDecrypt, but threads ctx and progress through to
+// AEADFileDecryptCtx for the AEAD case; the other, less commonly used
+// formats are comparatively quick and are decrypted without cancellation
+// support.
+func FileDecryptCtx(ctx context.Context, filepath string, passphrase []byte, delete, fix bool, keyfiles []string, progress suite.ProgressFunc) error {
+	if path.Ext(filepath) != sktExt {
+		return nil
+	}
+	version, err := peekVersion(filepath)
+	if err != nil {
+		return err
+	}
+	plain := stripFlags(version)
+	switch {
+	case bytes.Equal(plain, SKTAEADVersion), bytes.Equal(plain, SKTAEADVersionLegacy):
+		return AEADFileDecryptCtx(ctx, filepath, passphrase, delete, fix, keyfiles, progress)
+	case bytes.Equal(plain, SKTCascadeVersion):
+		return CascadeFileDecrypt(filepath, passphrase, delete)
+	case bytes.Equal(plain, SKTAESVersion), bytes.Equal(plain, SKTAESVersionLegacy):
+		return AESFileDecrypt(filepath, passphrase, delete, keyfiles)
+	case bytes.Equal(plain, SKTRC4Version):
+		return RC4FileDecrypt(filepath, passphrase, delete)
+	default:
+		return errors.New("unrecognized SKT version")
+	}
+}
+
+func peekVersion(filepath string) ([]byte, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	version := make([]byte, 5)
+	if _, err := io.ReadFull(f, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// RSAFileEncrypt encrypts filepath with a random session passphrase
+// wrapped for keyfile (the RSA public key). When mfKeyfiles is non-empty,
+// the session's AES key additionally requires every listed keyfile (see
+// CombineKeyfiles) - multi-factor on top of the RSA wrapping.
+func RSAFileEncrypt(filepath, keyfile string, delete bool, mfKeyfiles []string, ordered bool) error {
 	puk, err := ioutil.ReadFile(keyfile)
 	if err != nil {
 		return err
@@ -171,10 +614,16 @@ func RSAFileEncrypt(filepath, keyfile string, delete bool) error {
 	}
 	psize := make([]byte, pSizeLen)
 	binary.BigEndian.PutUint64(psize, uint64(len(pbytes)))
-	dest.Write(SKTRSAVersion)
+	dest.Write(withKFFlag(SKTRSAVersion, len(mfKeyfiles) != 0))
 	dest.Write(psize)
 	dest.Write(pbytes)
-	err = suite.StreamEnc(src, dest, passphrase, aesAlgo)
+	secret, err := writeKeyfileHeader(dest, mfKeyfiles, ordered)
+	if err != nil {
+		closeFile(src, dest)
+		os.Remove(dest.Name())
+		return err
+	}
+	err = suite.StreamEncMF(src, dest, passphrase, secret, aesAlgo)
 	closeFile(src, dest)
 	if err != nil {
 		os.Remove(dest.Name())
@@ -184,7 +633,9 @@ func RSAFileEncrypt(filepath, keyfile string, delete bool) error {
 	return nil
 }
 
-func RSAFileDecrypt(filepath, keyfile string, delete bool) error {
+// RSAFileDecrypt reverses RSAFileEncrypt. mfKeyfiles must list the same
+// paths passed as mfKeyfiles to RSAFileEncrypt, if any.
+func RSAFileDecrypt(filepath, keyfile string, delete bool, mfKeyfiles []string) error {
 	prk, err := ioutil.ReadFile(keyfile)
 	if err != nil {
 		return err
@@ -192,7 +643,12 @@ func RSAFileDecrypt(filepath, keyfile string, delete bool) error {
 	if path.Ext(filepath) != sktExt {
 		return nil
 	}
-	src, dest, err := beforeDecrypt(filepath, SKTRSAVersion)
+	version, err := peekVersion(filepath)
+	if err != nil {
+		return err
+	}
+	hasKF := hasKFFlag(version)
+	src, dest, isLegacy, err := beforeDecryptCompat(filepath, withKFFlag(SKTRSAVersion, hasKF), withKFFlag(SKTRSAVersionLegacy, hasKF))
 	if err != nil {
 		return err
 	}
@@ -202,9 +658,76 @@ func RSAFileDecrypt(filepath, keyfile string, delete bool) error {
 	src.Read(pbytes)
 	passphrase, err := rsa.RSADecrypt(pbytes, prk)
 	if err != nil {
+		closeFile(src, dest)
+		os.Remove(dest.Name())
+		return err
+	}
+	var secret []byte
+	if hasKF {
+		secret, err = readKeyfileHeader(src, mfKeyfiles)
+		if err != nil {
+			closeFile(src, dest)
+			os.Remove(dest.Name())
+			return err
+		}
+	}
+	if isLegacy {
+		err = suite.StreamDecLegacy(src, dest, passphrase, aesAlgo)
+	} else {
+		err = suite.StreamDecMF(src, dest, passphrase, secret, aesAlgo)
+	}
+	closeFile(src, dest)
+	if err != nil {
+		os.Remove(dest.Name())
+		return err
+	}
+	deleteFile(src, delete)
+	return nil
+}
+
+// DeniableFileEncrypt writes a headerless, suite.DeniableEncrypt-encoded
+// file at outPath: unlike every other File*Encrypt in this package, it
+// never derives outPath by appending sktExt (a fixed extension would be a
+// dead giveaway that the file is an encrypted volume, defeating the point
+// of deniability), so the caller must name it explicitly. The output has
+// no SKT magic bytes, and FileDecrypt cannot identify or route to it; it
+// must be decrypted with the explicit DeniableFileDecrypt.
+func DeniableFileEncrypt(filepath, outPath string, passphrase []byte, delete bool) error {
+	src, err := os.Open(filepath)
+	if err != nil {
+		return err
+	}
+	dest, err := os.Create(outPath)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	err = suite.DeniableEncrypt(src, dest, passphrase)
+	closeFile(src, dest)
+	if err != nil {
+		os.Remove(dest.Name())
+		return err
+	}
+	deleteFile(src, delete)
+	return nil
+}
+
+// DeniableFileDecrypt reverses DeniableFileEncrypt, reading filepath -
+// which may be named anything, since deniable output carries no
+// identifying extension - and writing the recovered plaintext to outPath.
+// There being no header to check, a wrong passphrase and a file that was
+// never encrypted in deniable mode both surface as suite.ErrAuthFailed.
+func DeniableFileDecrypt(filepath, outPath string, passphrase []byte, delete bool) error {
+	src, err := os.Open(filepath)
+	if err != nil {
+		return err
+	}
+	dest, err := os.Create(outPath)
+	if err != nil {
+		src.Close()
 		return err
 	}
-	err = suite.StreamDec(src, dest, passphrase, aesAlgo)
+	err = suite.DeniableDecrypt(src, dest, passphrase)
 	closeFile(src, dest)
 	if err != nil {
 		os.Remove(dest.Name())
@@ -0,0 +1,56 @@
+package kit
+
+import (
+	"io/ioutil"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// CombineKeyfiles folds the contents of one or more keyfiles into a single
+// 32-byte secret that suite.StreamEncryptMF/StreamDecryptMF XOR into the
+// Argon2id-derived key, giving multi-factor file encryption: both the
+// passphrase and every keyfile are required to reproduce the same master
+// secret.
+//
+// When ordered is false, the result is BLAKE2b-256 of each keyfile's
+// contents XORed together, so it does not depend on the order paths were
+// given in. When ordered is true, the per-keyfile hashes are concatenated
+// (H(kf1)||H(kf2)||...) and hashed again, so swapping two keyfiles changes
+// the result.
+func CombineKeyfiles(paths []string, ordered bool) ([]byte, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if ordered {
+		var concat []byte
+		for _, p := range paths {
+			h, err := hashKeyfile(p)
+			if err != nil {
+				return nil, err
+			}
+			concat = append(concat, h...)
+		}
+		sum := blake2b.Sum256(concat)
+		return sum[:], nil
+	}
+	combined := make([]byte, blake2b.Size256)
+	for _, p := range paths {
+		h, err := hashKeyfile(p)
+		if err != nil {
+			return nil, err
+		}
+		for i := range combined {
+			combined[i] ^= h[i]
+		}
+	}
+	return combined, nil
+}
+
+func hashKeyfile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := blake2b.Sum256(data)
+	return sum[:], nil
+}
@@ -0,0 +1,152 @@
+package suite
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"io"
+	"io/ioutil"
+
+	"github.com/HACKERALERT/serpent"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	cascadeChaChaNonceSize = chacha20.NonceSizeX
+	cascadeSerpentIVSize   = 16
+	cascadeMacSize         = blake2b.Size256
+)
+
+// deriveCascadeKeys splits a single Argon2id master key into two
+// independent 32-byte keys via HKDF-SHA3, one per cipher in the cascade,
+// so compromise of one cipher's key does not expose the other's.
+func deriveCascadeKeys(master []byte) (chachaKey, serpentKey []byte, err error) {
+	chachaKey = make([]byte, 32)
+	if _, err = io.ReadFull(hkdf.New(sha3.New256, master, nil, []byte("chacha")), chachaKey); err != nil {
+		return nil, nil, err
+	}
+	serpentKey = make([]byte, 32)
+	if _, err = io.ReadFull(hkdf.New(sha3.New256, master, nil, []byte("serpent")), serpentKey); err != nil {
+		return nil, nil, err
+	}
+	return chachaKey, serpentKey, nil
+}
+
+// cascadeEncrypt pipes plaintext through XChaCha20 and then Serpent-CTR,
+// each keyed independently (see deriveCascadeKeys), and authenticates the
+// resulting stream with a single BLAKE2b-256 MAC. The header written ahead
+// of the ciphertext is chachaNonce||serpentIV; the MAC is appended after
+// the ciphertext.
+//
+// The whole plaintext is read into memory before anything is written, and
+// cascadeDecrypt likewise reads the whole input so it can verify the MAC
+// before writing any plaintext. Unlike the AES path in kit/aes, neither
+// direction streams in fixed-size chunks.
+func cascadeEncrypt(src io.Reader, dest io.Writer, master []byte) error {
+	chachaKey, serpentKey, err := deriveCascadeKeys(master)
+	if err != nil {
+		return err
+	}
+
+	chachaNonce := make([]byte, cascadeChaChaNonceSize)
+	if _, err = rand.Read(chachaNonce); err != nil {
+		return err
+	}
+	serpentIV := make([]byte, cascadeSerpentIVSize)
+	if _, err = rand.Read(serpentIV); err != nil {
+		return err
+	}
+
+	plaintext, err := ioutil.ReadAll(src)
+	if err != nil {
+		return err
+	}
+
+	chachaStream, err := chacha20.NewUnauthenticatedCipher(chachaKey, chachaNonce)
+	if err != nil {
+		return err
+	}
+	stage1 := make([]byte, len(plaintext))
+	chachaStream.XORKeyStream(stage1, plaintext)
+
+	serpentBlock, err := serpent.NewCipher(serpentKey)
+	if err != nil {
+		return err
+	}
+	ctr := cipher.NewCTR(serpentBlock, serpentIV)
+	stage2 := make([]byte, len(stage1))
+	ctr.XORKeyStream(stage2, stage1)
+
+	mac, err := blake2b.New256(master)
+	if err != nil {
+		return err
+	}
+	w := io.MultiWriter(dest, mac)
+	if _, err = w.Write(chachaNonce); err != nil {
+		return err
+	}
+	if _, err = w.Write(serpentIV); err != nil {
+		return err
+	}
+	if _, err = w.Write(stage2); err != nil {
+		return err
+	}
+
+	_, err = dest.Write(mac.Sum(nil))
+	return err
+}
+
+// cascadeDecrypt reverses cascadeEncrypt: Serpent-CTR is undone first, then
+// XChaCha20, after the BLAKE2b-256 MAC over the header+ciphertext has been
+// verified.
+func cascadeDecrypt(src io.Reader, dest io.Writer, master []byte) error {
+	chachaKey, serpentKey, err := deriveCascadeKeys(master)
+	if err != nil {
+		return err
+	}
+
+	body, err := ioutil.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	if len(body) < cascadeChaChaNonceSize+cascadeSerpentIVSize+cascadeMacSize {
+		return ErrAuthFailed
+	}
+
+	headerAndCiphertext := body[:len(body)-cascadeMacSize]
+	tag := body[len(body)-cascadeMacSize:]
+
+	mac, err := blake2b.New256(master)
+	if err != nil {
+		return err
+	}
+	mac.Write(headerAndCiphertext)
+	if subtle.ConstantTimeCompare(tag, mac.Sum(nil)) != 1 {
+		return ErrAuthFailed
+	}
+
+	chachaNonce := headerAndCiphertext[:cascadeChaChaNonceSize]
+	serpentIV := headerAndCiphertext[cascadeChaChaNonceSize : cascadeChaChaNonceSize+cascadeSerpentIVSize]
+	stage2 := headerAndCiphertext[cascadeChaChaNonceSize+cascadeSerpentIVSize:]
+
+	serpentBlock, err := serpent.NewCipher(serpentKey)
+	if err != nil {
+		return err
+	}
+	ctr := cipher.NewCTR(serpentBlock, serpentIV)
+	stage1 := make([]byte, len(stage2))
+	ctr.XORKeyStream(stage1, stage2)
+
+	chachaStream, err := chacha20.NewUnauthenticatedCipher(chachaKey, chachaNonce)
+	if err != nil {
+		return err
+	}
+	plaintext := make([]byte, len(stage1))
+	chachaStream.XORKeyStream(plaintext, stage1)
+
+	_, err = dest.Write(plaintext)
+	return err
+}
@@ -0,0 +1,63 @@
+package suite
+
+import (
+	"context"
+	"io"
+)
+
+// ProgressFunc is invoked from the copy loop of a Stream*Ctx call with the
+// number of bytes read from src so far (plaintext when encrypting,
+// ciphertext when decrypting) and, if known, the total size. total is 0 when the caller couldn't determine it in advance (e.g.
+// encrypting from a pipe).
+type ProgressFunc func(bytesDone, bytesTotal int64)
+
+// ctxReader wraps an io.Reader so Read returns ctx.Err() as soon as ctx is
+// canceled, instead of waiting for the underlying copy loop to finish a
+// buffer's worth of work, and reports bytes read to progress as it goes.
+type ctxReader struct {
+	ctx      context.Context
+	r        io.Reader
+	total    int64
+	done     int64
+	progress ProgressFunc
+}
+
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	select {
+	case <-cr.ctx.Done():
+		return 0, cr.ctx.Err()
+	default:
+	}
+	n, err := cr.r.Read(p)
+	if n > 0 {
+		cr.done += int64(n)
+		if cr.progress != nil {
+			cr.progress(cr.done, cr.total)
+		}
+	}
+	return n, err
+}
+
+// StreamEncryptCtx is StreamEncrypt, but returns promptly with ctx.Err()
+// if ctx is canceled mid-stream, and calls progress (if non-nil) as
+// plaintext is read from src. total is src's size if known, or 0.
+func StreamEncryptCtx(ctx context.Context, src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm, total int64, progress ProgressFunc) error {
+	cr := &ctxReader{ctx: ctx, r: src, total: total, progress: progress}
+	return StreamEncrypt(cr, dest, passphrase, algorithm)
+}
+
+// NewCtxReader exposes ctxReader to callers outside the package (such as
+// kit's keyfile/Reed-Solomon aware encrypt helpers) that need to splice
+// context cancellation and progress reporting into a StreamEncryptMF/
+// StreamDecryptMF call, which have no Ctx-suffixed counterpart of their own.
+func NewCtxReader(ctx context.Context, r io.Reader, total int64, progress ProgressFunc) io.Reader {
+	return &ctxReader{ctx: ctx, r: r, total: total, progress: progress}
+}
+
+// StreamDecryptCtx is StreamDecrypt, but returns promptly with ctx.Err()
+// if ctx is canceled mid-stream, and calls progress (if non-nil) as
+// ciphertext is read from src. total is src's size if known, or 0.
+func StreamDecryptCtx(ctx context.Context, src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm, total int64, progress ProgressFunc) error {
+	cr := &ctxReader{ctx: ctx, r: src, total: total, progress: progress}
+	return StreamDecrypt(cr, dest, passphrase, algorithm)
+}
@@ -0,0 +1,154 @@
+package suite
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha512"
+	"io"
+
+	"github.com/cinus-ue/securekit/kit/key"
+	"golang.org/x/crypto/hkdf"
+)
+
+// deniableInfo is the fixed HKDF info string every deniable-mode parameter
+// is derived under, so a deniable file carries no salt, IV, or cost
+// parameters of its own: the passphrase alone reproduces them.
+const deniableInfo = "skt-deniable-v1"
+
+// deniableParams is the fixed Argon2id cost for deniable mode. It cannot be
+// tuned per file like KDFPreset, since there is nowhere to record a chosen
+// value without breaking headerlessness.
+var deniableParams = key.Default
+
+// deniableMaterial derives the Argon2id salt and the AES-CTR IV
+// deterministically from passphrase, so encrypting under the same
+// passphrase always reproduces the same key stream. That repetition is the
+// price of a file with no header at all; callers should treat deniable
+// mode as single-use-passphrase-per-file.
+func deniableMaterial(passphrase []byte) (salt, iv []byte, err error) {
+	r := hkdf.New(sha512.New, passphrase, nil, []byte(deniableInfo))
+	material := make([]byte, key.SaltLen+aeadIVSize)
+	if _, err = io.ReadFull(r, material); err != nil {
+		return nil, nil, err
+	}
+	return material[:key.SaltLen], material[key.SaltLen:], nil
+}
+
+// DeniableEncrypt writes ciphertext||tag to dest, with no magic bytes, KDF
+// header, or IV: every byte is either AES-CTR output or the trailing
+// HMAC-SHA512 tag, both indistinguishable from random data. It is
+// aesCTRHmacEncrypt with the IV derived from passphrase instead of
+// crypto/rand, and omitted from the output.
+func DeniableEncrypt(src io.Reader, dest io.Writer, passphrase []byte) error {
+	salt, iv, err := deniableMaterial(passphrase)
+	if err != nil {
+		return err
+	}
+	encKey, _, err := key.DeriveKeyWithParams(passphrase, salt, deniableParams)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return err
+	}
+	hmacKey, err := deriveHMACKey(encKey)
+	if err != nil {
+		return err
+	}
+
+	ctr := cipher.NewCTR(block, iv)
+	mac := hmac.New(sha512.New, hmacKey)
+	mac.Write(iv)
+	w := io.MultiWriter(dest, mac)
+
+	buf := make([]byte, aeadBufSize)
+	for {
+		n, rerr := src.Read(buf)
+		if n != 0 {
+			outBuf := make([]byte, n)
+			ctr.XORKeyStream(outBuf, buf[:n])
+			if _, werr := w.Write(outBuf); werr != nil {
+				return werr
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+
+	_, err = dest.Write(mac.Sum(nil))
+	return err
+}
+
+// DeniableDecrypt reverses DeniableEncrypt. It re-derives the same salt, IV
+// and key from passphrase and returns ErrAuthFailed if the trailing tag
+// doesn't match - indistinguishable from simply having the wrong
+// passphrase, which is the point: there is no header to reject first.
+func DeniableDecrypt(src io.Reader, dest io.Writer, passphrase []byte) error {
+	salt, iv, err := deniableMaterial(passphrase)
+	if err != nil {
+		return err
+	}
+	encKey, _, err := key.DeriveKeyWithParams(passphrase, salt, deniableParams)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return err
+	}
+	hmacKey, err := deriveHMACKey(encKey)
+	if err != nil {
+		return err
+	}
+
+	ctr := cipher.NewCTR(block, iv)
+	mac := hmac.New(sha512.New, hmacKey)
+	mac.Write(iv)
+
+	buf := bufio.NewReaderSize(src, aeadBufSize)
+	tag := make([]byte, aeadTagSize)
+	for {
+		b, perr := buf.Peek(aeadBufSize)
+		if perr != nil && perr != io.EOF {
+			return perr
+		}
+
+		limit := len(b) - aeadTagSize
+		if perr == io.EOF {
+			left := buf.Buffered()
+			if left < aeadTagSize {
+				return ErrAuthFailed
+			}
+			copy(tag, b[left-aeadTagSize:left])
+			if left == aeadTagSize {
+				break
+			}
+		}
+
+		mac.Write(b[:limit])
+		outBuf := make([]byte, limit)
+		_, _ = buf.Read(b[:limit])
+		ctr.XORKeyStream(outBuf, b[:limit])
+		if _, werr := dest.Write(outBuf); werr != nil {
+			return werr
+		}
+
+		if perr == io.EOF {
+			break
+		}
+	}
+
+	if !hmac.Equal(tag, mac.Sum(nil)) {
+		return ErrAuthFailed
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package suite
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/klauspost/reedsolomon"
+)
+
+// Reed-Solomon framing: each 128-byte data block is a 2-byte big-endian
+// length prefix followed by up to 126 payload bytes (the last block is
+// typically shorter and zero-padded), RS-coded into a 136-byte codeword
+// (128 data shards of 1 byte + 8 parity shards of 1 byte). klauspost/
+// reedsolomon is an erasure coder, not a classical error-corrector: it can
+// only recompute shards it's told are missing, not locate ones that are
+// silently wrong. ReedSolomonReader works around that for the common
+// single-corrupted-byte case by exhaustively trying each of the 136 shards
+// as the erasure (see reconstructSingleErasure) - beyond one bad byte per
+// codeword, a block is unrecoverable.
+const (
+	rsDataSize    = 128
+	rsParitySize  = 8
+	rsBlockSize   = rsDataSize + rsParitySize
+	rsPayloadSize = rsDataSize - 2
+)
+
+// ErrRSUnrecoverable is returned by ReedSolomonReader when a codeword fails
+// verification and the reader was not configured to substitute zero-filled
+// data for it (see NewReedSolomonReader's fix argument).
+var ErrRSUnrecoverable = errors.New("reed-solomon: block unrecoverable")
+
+// ReedSolomonWriter chunks everything written to it into rsPayloadSize data
+// blocks and emits one rsBlockSize codeword per block to the underlying
+// writer. Callers must call Close to flush the final, possibly short,
+// block.
+type ReedSolomonWriter struct {
+	w   io.Writer
+	enc reedsolomon.Encoder
+	buf []byte
+	n   int
+}
+
+func NewReedSolomonWriter(w io.Writer) (*ReedSolomonWriter, error) {
+	enc, err := reedsolomon.New(rsDataSize, rsParitySize)
+	if err != nil {
+		return nil, err
+	}
+	return &ReedSolomonWriter{w: w, enc: enc, buf: make([]byte, rsPayloadSize)}, nil
+}
+
+func (rw *ReedSolomonWriter) Write(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		n := copy(rw.buf[rw.n:], p)
+		rw.n += n
+		p = p[n:]
+		total += n
+		if rw.n == rsPayloadSize {
+			if err := rw.flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+// Close flushes any buffered partial block. It is a no-op, not an error, to
+// call Close with nothing buffered.
+func (rw *ReedSolomonWriter) Close() error {
+	if rw.n == 0 {
+		return nil
+	}
+	return rw.flush()
+}
+
+func (rw *ReedSolomonWriter) flush() error {
+	block := make([]byte, rsDataSize)
+	binary.BigEndian.PutUint16(block[:2], uint16(rw.n))
+	copy(block[2:], rw.buf[:rw.n])
+
+	shards := make([][]byte, rsBlockSize)
+	for i := 0; i < rsDataSize; i++ {
+		shards[i] = []byte{block[i]}
+	}
+	for i := rsDataSize; i < rsBlockSize; i++ {
+		shards[i] = make([]byte, 1)
+	}
+	if err := rw.enc.Encode(shards); err != nil {
+		return err
+	}
+
+	codeword := make([]byte, rsBlockSize)
+	for i, s := range shards {
+		codeword[i] = s[0]
+	}
+	rw.n = 0
+	_, err := rw.w.Write(codeword)
+	return err
+}
+
+// ReedSolomonReader reverses ReedSolomonWriter, transparently repairing a
+// codeword with a single corrupted byte (see reconstructSingleErasure).
+// When a codeword has more damage than that and fix is true, it is
+// replaced with a zero-filled payload of the full rsPayloadSize instead of
+// returning ErrRSUnrecoverable, matching the Picocrypt-style "attempt
+// recovery" behavior - lossy, and the trailing AEAD tag will then fail to
+// verify since the substituted bytes don't match what was encrypted, but
+// it keeps the stream framing (and everything after this block) intact
+// rather than desyncing the rest of the file. Call Lossy after a decrypt
+// fails to tell that expected tag mismatch apart from tampering.
+type ReedSolomonReader struct {
+	r     io.Reader
+	dec   reedsolomon.Encoder
+	fix   bool
+	buf   []byte
+	pos   int
+	done  bool
+	lossy bool
+}
+
+// Lossy reports whether any block read so far had more damage than a
+// single erasure could locate and was substituted with zero-filled data
+// (only possible when fix is true). Callers that authenticate the
+// decrypted stream use this to tell a substitution-induced auth failure
+// apart from tampering or a wrong passphrase.
+func (rr *ReedSolomonReader) Lossy() bool {
+	return rr.lossy
+}
+
+func NewReedSolomonReader(r io.Reader, fix bool) (*ReedSolomonReader, error) {
+	dec, err := reedsolomon.New(rsDataSize, rsParitySize)
+	if err != nil {
+		return nil, err
+	}
+	return &ReedSolomonReader{r: r, dec: dec, fix: fix}, nil
+}
+
+func (rr *ReedSolomonReader) Read(p []byte) (int, error) {
+	if rr.pos >= len(rr.buf) {
+		if rr.done {
+			return 0, io.EOF
+		}
+		if err := rr.nextBlock(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, rr.buf[rr.pos:])
+	rr.pos += n
+	return n, nil
+}
+
+func (rr *ReedSolomonReader) nextBlock() error {
+	codeword := make([]byte, rsBlockSize)
+	_, err := io.ReadFull(rr.r, codeword)
+	if err == io.EOF {
+		rr.done = true
+		return io.EOF
+	}
+	if err != nil {
+		return err
+	}
+
+	shards := make([][]byte, rsBlockSize)
+	for i := range shards {
+		shards[i] = []byte{codeword[i]}
+	}
+
+	ok, verr := rr.dec.Verify(shards)
+	if verr != nil {
+		return verr
+	}
+	if !ok {
+		if fixed, recovered := rr.reconstructSingleErasure(codeword); recovered {
+			shards = fixed
+		} else if rr.fix {
+			// More damage than a single erasure can locate; substitute a
+			// full payload of zeroes so the length prefix isn't read from
+			// garbage and the stream framing for every block after this
+			// one stays intact. The caller's AEAD tag will not verify;
+			// Lossy lets it recognize that and commit the best-effort
+			// plaintext instead of discarding it.
+			rr.lossy = true
+			rr.buf = make([]byte, rsPayloadSize)
+			rr.pos = 0
+			return nil
+		} else {
+			return ErrRSUnrecoverable
+		}
+	}
+
+	block := make([]byte, rsDataSize)
+	for i := 0; i < rsDataSize; i++ {
+		block[i] = shards[i][0]
+	}
+	length := binary.BigEndian.Uint16(block[:2])
+	if length > rsPayloadSize {
+		length = rsPayloadSize
+	}
+	rr.buf = block[2 : 2+length]
+	rr.pos = 0
+	return nil
+}
+
+// reconstructSingleErasure tries, in turn, treating each of the
+// rsBlockSize shards as erased and asks the decoder to recompute it from
+// the rest; if erasing exactly one shard makes the codeword verify, that
+// shard was the corrupted one and the repaired shards are returned. This
+// is the only way to locate - not just detect - a corrupted byte with an
+// erasure coder: reconstructing from the wrong hypothesis leaves the
+// codeword inconsistent, so at most one hypothesis can succeed.
+func (rr *ReedSolomonReader) reconstructSingleErasure(codeword []byte) ([][]byte, bool) {
+	for erased := 0; erased < rsBlockSize; erased++ {
+		shards := make([][]byte, rsBlockSize)
+		for i := range shards {
+			if i == erased {
+				continue
+			}
+			shards[i] = []byte{codeword[i]}
+		}
+		if err := rr.dec.Reconstruct(shards); err != nil {
+			continue
+		}
+		if ok, verr := rr.dec.Verify(shards); verr == nil && ok {
+			return shards, true
+		}
+	}
+	return nil, false
+}
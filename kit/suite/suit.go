@@ -15,10 +15,12 @@ import (
 type Algorithm string
 
 const (
-	RC4       = Algorithm("RC4")
-	RSA       = Algorithm("RSA")
-	Aes256Gcm = Algorithm("AES-256-GCM")
-	Aes256Ctr = Algorithm("AES-256-CTR")
+	RC4                   = Algorithm("RC4")
+	RSA                   = Algorithm("RSA")
+	Aes256Gcm             = Algorithm("AES-256-GCM")
+	Aes256Ctr             = Algorithm("AES-256-CTR")
+	Aes256CtrHmac         = Algorithm("AES-256-CTR-HMAC")
+	CascadeXChaChaSerpent = Algorithm("XCHACHA20-SERPENT-CASCADE")
 )
 
 var (
@@ -55,15 +57,54 @@ func BlockDecrypt(ciphertext, passphrase []byte, algorithm Algorithm) ([]byte, e
 	}
 }
 
+// KDFPreset selects the Argon2id cost embedded in the header of new
+// Aes256Ctr/Aes256CtrHmac streams. It defaults to key.Default; set it to
+// key.Paranoid before calling StreamEncrypt for the high-cost preset.
+var KDFPreset = key.Default
+
+func writeKDFHeader(dest io.Writer, params key.KDFParams, salt []byte) error {
+	if _, err := dest.Write(key.EncodeParams(params)); err != nil {
+		return err
+	}
+	_, err := dest.Write(salt)
+	return err
+}
+
+func readKDFHeader(src io.Reader) (key.KDFParams, []byte, error) {
+	params, err := key.DecodeParams(src)
+	if err != nil {
+		return key.KDFParams{}, nil, err
+	}
+	salt := make([]byte, key.SaltLen)
+	if _, err := io.ReadFull(src, salt); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = key.ErrShortSalt
+		}
+		return key.KDFParams{}, nil, err
+	}
+	return params, salt, nil
+}
+
 func StreamEncrypt(src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm) (err error) {
 	switch algorithm {
 	case Aes256Ctr:
-		k, salt, _ := key.DeriveKey(passphrase, nil, 32)
-		_, err = dest.Write(salt)
-		if err != nil {
+		k, salt, _ := key.DeriveKeyWithParams(passphrase, nil, KDFPreset)
+		if err = writeKDFHeader(dest, KDFPreset, salt); err != nil {
 			return err
 		}
 		return aes.AESCTREncrypt(src, dest, k)
+	case Aes256CtrHmac:
+		k, salt, _ := key.DeriveKeyWithParams(passphrase, nil, KDFPreset)
+		if err = writeKDFHeader(dest, KDFPreset, salt); err != nil {
+			return err
+		}
+		return aesCTRHmacEncrypt(src, dest, k)
+	case CascadeXChaChaSerpent:
+		k, salt, _ := key.DeriveKeyWithParams(passphrase, nil, KDFPreset)
+		if err = writeKDFHeader(dest, KDFPreset, salt); err != nil {
+			return err
+		}
+		return cascadeEncrypt(src, dest, k)
 	case RC4:
 		tag := hash.SHA256(passphrase)
 		_, err = dest.Write(tag)
@@ -79,13 +120,26 @@ func StreamEncrypt(src io.Reader, dest io.Writer, passphrase []byte, algorithm A
 func StreamDecrypt(src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm) (err error) {
 	switch algorithm {
 	case Aes256Ctr:
-		salt := make([]byte, key.SaltLen)
-		_, err := src.Read(salt)
+		params, salt, err := readKDFHeader(src)
 		if err != nil {
 			return err
 		}
-		k, _, _ := key.DeriveKey(passphrase, salt, 32)
+		k, _, _ := key.DeriveKeyWithParams(passphrase, salt, params)
 		return aes.AESCTRDecrypt(src, dest, k)
+	case Aes256CtrHmac:
+		params, salt, err := readKDFHeader(src)
+		if err != nil {
+			return err
+		}
+		k, _, _ := key.DeriveKeyWithParams(passphrase, salt, params)
+		return aesCTRHmacDecrypt(src, dest, k)
+	case CascadeXChaChaSerpent:
+		params, salt, err := readKDFHeader(src)
+		if err != nil {
+			return err
+		}
+		k, _, _ := key.DeriveKeyWithParams(passphrase, salt, params)
+		return cascadeDecrypt(src, dest, k)
 	case RC4:
 		tag := make([]byte, sha256.Size)
 		_, err = src.Read(tag)
@@ -101,6 +155,142 @@ func StreamDecrypt(src io.Reader, dest io.Writer, passphrase []byte, algorithm A
 	}
 }
 
+// mixKeyfileSecret XORs secret into k in place, repeating secret as needed
+// to cover k's length. It is a no-op when secret is empty, so callers can
+// pass it unconditionally whether or not keyfiles are in use.
+func mixKeyfileSecret(k, secret []byte) {
+	if len(secret) == 0 {
+		return
+	}
+	for i := range k {
+		k[i] ^= secret[i%len(secret)]
+	}
+}
+
+// StreamEncryptMF is StreamEncrypt for the CTR-family algorithms, except
+// the Argon2id-derived key is XORed with keyfileSecret (typically
+// kit.CombineKeyfiles's output) before use, so both the passphrase and the
+// keyfiles are required to decrypt. Pass a nil/empty keyfileSecret to get
+// passphrase-only behavior identical to StreamEncrypt.
+func StreamEncryptMF(src io.Reader, dest io.Writer, passphrase, keyfileSecret []byte, algorithm Algorithm) (err error) {
+	switch algorithm {
+	case Aes256Ctr:
+		k, salt, _ := key.DeriveKeyWithParams(passphrase, nil, KDFPreset)
+		mixKeyfileSecret(k, keyfileSecret)
+		if err = writeKDFHeader(dest, KDFPreset, salt); err != nil {
+			return err
+		}
+		return aes.AESCTREncrypt(src, dest, k)
+	case Aes256CtrHmac:
+		k, salt, _ := key.DeriveKeyWithParams(passphrase, nil, KDFPreset)
+		mixKeyfileSecret(k, keyfileSecret)
+		if err = writeKDFHeader(dest, KDFPreset, salt); err != nil {
+			return err
+		}
+		return aesCTRHmacEncrypt(src, dest, k)
+	default:
+		return algoErr
+	}
+}
+
+// StreamDecryptMF reverses StreamEncryptMF.
+func StreamDecryptMF(src io.Reader, dest io.Writer, passphrase, keyfileSecret []byte, algorithm Algorithm) (err error) {
+	switch algorithm {
+	case Aes256Ctr:
+		params, salt, err := readKDFHeader(src)
+		if err != nil {
+			return err
+		}
+		k, _, _ := key.DeriveKeyWithParams(passphrase, salt, params)
+		mixKeyfileSecret(k, keyfileSecret)
+		return aes.AESCTRDecrypt(src, dest, k)
+	case Aes256CtrHmac:
+		params, salt, err := readKDFHeader(src)
+		if err != nil {
+			return err
+		}
+		k, _, _ := key.DeriveKeyWithParams(passphrase, salt, params)
+		mixKeyfileSecret(k, keyfileSecret)
+		return aesCTRHmacDecrypt(src, dest, k)
+	default:
+		return algoErr
+	}
+}
+
+// legacyDeriveKey reproduces the simple KDF that key.DeriveKey stood in for
+// before this package existed: a single SHA-256 of passphrase||salt, with
+// no tunable cost and nothing beyond a bare salt to store in the header -
+// matching the pre-KDFParams call sites this package's callers used
+// (key.DeriveKey(passphrase, salt, 32), no params argument). It is not
+// Argon2id: a legacy file was never derived with Argon2id, tunable cost or
+// not, so decrypting it with key.DeriveKeyWithParams under any cost
+// (Default included) reproduces the wrong key and silently yields garbage
+// plaintext for Aes256Ctr, which has no MAC to catch that. Only
+// StreamDecryptLegacy calls this.
+func legacyDeriveKey(passphrase, salt []byte, keyLen int) []byte {
+	sum := hash.SHA256(append(append([]byte{}, passphrase...), salt...))
+	for len(sum) < keyLen {
+		sum = append(sum, hash.SHA256(sum)...)
+	}
+	return sum[:keyLen]
+}
+
+// StreamDecryptLegacy decrypts an Aes256Ctr/Aes256CtrHmac stream written by
+// a pre-KDFParams version of this package: the header is a bare salt, with
+// no embedded cost parameters, derived under legacyDeriveKey rather than
+// Argon2id. Callers (see kit.AESFileDecrypt) select this over StreamDecrypt
+// based on the file's SKT version bytes.
+func StreamDecryptLegacy(src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm) (err error) {
+	switch algorithm {
+	case Aes256Ctr:
+		salt := make([]byte, key.SaltLen)
+		if _, err = io.ReadFull(src, salt); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = key.ErrShortSalt
+			}
+			return err
+		}
+		k := legacyDeriveKey(passphrase, salt, 32)
+		return aes.AESCTRDecrypt(src, dest, k)
+	case Aes256CtrHmac:
+		salt := make([]byte, key.SaltLen)
+		if _, err = io.ReadFull(src, salt); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = key.ErrShortSalt
+			}
+			return err
+		}
+		k := legacyDeriveKey(passphrase, salt, 32)
+		return aesCTRHmacDecrypt(src, dest, k)
+	default:
+		return algoErr
+	}
+}
+
+// StreamEnc and StreamDec are the short names kit.*FileEncrypt/Decrypt call
+// through; they simply forward to StreamEncrypt/StreamDecrypt.
+func StreamEnc(src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm) error {
+	return StreamEncrypt(src, dest, passphrase, algorithm)
+}
+
+func StreamDec(src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm) error {
+	return StreamDecrypt(src, dest, passphrase, algorithm)
+}
+
+// StreamDecLegacy forwards to StreamDecryptLegacy.
+func StreamDecLegacy(src io.Reader, dest io.Writer, passphrase []byte, algorithm Algorithm) error {
+	return StreamDecryptLegacy(src, dest, passphrase, algorithm)
+}
+
+// StreamEncMF and StreamDecMF forward to StreamEncryptMF/StreamDecryptMF.
+func StreamEncMF(src io.Reader, dest io.Writer, passphrase, keyfileSecret []byte, algorithm Algorithm) error {
+	return StreamEncryptMF(src, dest, passphrase, keyfileSecret, algorithm)
+}
+
+func StreamDecMF(src io.Reader, dest io.Writer, passphrase, keyfileSecret []byte, algorithm Algorithm) error {
+	return StreamDecryptMF(src, dest, passphrase, keyfileSecret, algorithm)
+}
+
 func Sign(hashed, privateKey []byte, algorithm Algorithm) ([]byte, error) {
 	switch algorithm {
 	case RSA:
@@ -0,0 +1,30 @@
+// Package progress adapts suite.ProgressFunc to a terminal progress bar for
+// CLI use.
+package progress
+
+import (
+	"github.com/cinus-ue/securekit/kit/suite"
+	"github.com/schollz/progressbar/v3"
+)
+
+// Bar renders a terminal progress bar described by label and returns a
+// suite.ProgressFunc that drives it. total may be 0 if the caller doesn't
+// know the size in advance, in which case the bar starts as a byte counter
+// with no percentage and switches to one as soon as the returned
+// ProgressFunc is first called with a known bytesTotal - callers that
+// already know the size up front (e.g. after os.Stat) can still pass it
+// directly to skip that switch.
+func Bar(label string, total int64) suite.ProgressFunc {
+	if total <= 0 {
+		total = -1
+	}
+	bar := progressbar.DefaultBytes(total, label)
+	known := total > 0
+	return func(bytesDone, bytesTotal int64) {
+		if !known && bytesTotal > 0 {
+			bar.ChangeMax64(bytesTotal)
+			known = true
+		}
+		bar.Set64(bytesDone)
+	}
+}